@@ -0,0 +1,256 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// cache stores the values of environment variables already resolved by
+// the Get* helpers and Unmarshal, keyed by variable name. It exists to
+// avoid repeated lookups of environment variables, which can be
+// expensive. cacheMu guards it so that a reload triggered by Watch can
+// safely run concurrently with readers.
+//
+// Load (and therefore Watch's reload) holds cacheMu for its entire
+// os.Setenv loop plus the cache reset, via applyAndCache, and every Get*
+// helper holds the same lock for its entire cache-check-then-fallback
+// sequence via lockedLookup/lockedLookupWithFallback. That means a Get*
+// call for a key that isn't cached yet can't interleave with a reload:
+// it either runs to completion before the reload's critical section
+// starts, or blocks until the reload finishes and then sees the fully
+// reloaded environment. There is no window where it can observe a
+// partially-applied set of variables.
+var (
+	cacheMu sync.RWMutex
+	cache   = make(map[string]string)
+)
+
+// ClearCache clears the cache of environment variables.
+func ClearCache() {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache = make(map[string]string)
+}
+
+func cacheGet(key string) (string, bool) {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+	v, ok := cache[key]
+	return v, ok
+}
+
+func cacheSet(key, value string) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	cache[key] = value
+}
+
+// lockedLookup returns key's cached value, or looks it up in the process
+// environment and caches it if found, all under a single critical
+// section so the check and the fallback lookup can't straddle a reload.
+// ok is false, and nothing is cached, if key is unset.
+func lockedLookup(key string) (value string, ok bool) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if v, ok := cache[key]; ok {
+		return v, true
+	}
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return "", false
+	}
+	cache[key] = v
+	return v, true
+}
+
+// lockedLookupWithFallback behaves like lockedLookup, except an unset or
+// empty key resolves to (and caches) fallback instead of reporting a
+// miss, matching GetString's long-standing behavior of caching its
+// fallback value too.
+func lockedLookupWithFallback(key, fallback string) string {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	if v, ok := cache[key]; ok {
+		return v
+	}
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		v = fallback
+	}
+	cache[key] = v
+	return v
+}
+
+// applyAndCache sets every variable in vars via os.Setenv and then
+// clears the cache, all under one critical section.
+// This is what makes a reload (a fresh Load call, whether triggered
+// directly or by Watch) atomic with respect to the lockedLookup*
+// helpers above: no Get* call can see env vars from some keys already
+// updated and others not yet.
+func applyAndCache(vars map[string]string) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	for key, value := range vars {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	cache = make(map[string]string)
+	return nil
+}
+
+// SetCacheForTesting swaps the package-level cache for newCache and
+// returns a restore function that puts the previous cache back. It is
+// used by the dotenvtest subpackage to give a test a fresh cache. There
+// is only one package-level cache, so the swap is seen by every
+// goroutine: tests running in parallel share whichever cache was
+// swapped in last and do not get isolated caches.
+func SetCacheForTesting(newCache map[string]string) (restore func()) {
+	cacheMu.Lock()
+	previous := cache
+	cache = newCache
+	cacheMu.Unlock()
+
+	return func() {
+		cacheMu.Lock()
+		cache = previous
+		cacheMu.Unlock()
+	}
+}
+
+// reloadMu guards the registered OnReload callbacks.
+var (
+	reloadMu        sync.Mutex
+	reloadCallbacks []func(changed []string)
+)
+
+// OnReload registers a hook that is invoked after Watch reloads one or
+// more files, with the list of filenames that changed. Hooks run after
+// the cache has been cleared and the new values have been applied via
+// Load, so they can safely rebuild dependent state (DB pools, HTTP
+// clients, log levels) by calling the Get* helpers again.
+func OnReload(fn func(changed []string)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadCallbacks = append(reloadCallbacks, fn)
+}
+
+func notifyReload(changed []string) {
+	reloadMu.Lock()
+	callbacks := make([]func(changed []string), len(reloadCallbacks))
+	copy(callbacks, reloadCallbacks)
+	reloadMu.Unlock()
+
+	for _, cb := range callbacks {
+		cb(changed)
+	}
+}
+
+// debounceWindow is how long Watch coalesces bursts of filesystem events
+// (editors commonly write a file several times in quick succession, e.g.
+// via a temp-file-then-rename save) before triggering a single reload.
+const debounceWindow = 100 * time.Millisecond
+
+// Watch watches the given .env files for writes and renames and
+// automatically re-invokes Load (which also clears the cache) whenever
+// they change, debouncing bursts of events within a 100ms window. It blocks
+// until ctx is canceled, at which point it stops the underlying watcher
+// and returns ctx.Err().
+//
+// Register OnReload to be notified after each reload so dependent state
+// can be rebuilt.
+func Watch(ctx context.Context, filenames ...string) error {
+	if len(filenames) == 0 {
+		filenames = []string{".env"}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool, len(filenames))
+	dirs := make(map[string]bool)
+	for _, f := range filenames {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		watched[abs] = true
+		dirs[filepath.Dir(abs)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+
+	var (
+		timer   *time.Timer
+		pending = make(map[string]bool)
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	reload := func() {
+		changed := make([]string, 0, len(pending))
+		for f := range pending {
+			changed = append(changed, f)
+		}
+		pending = make(map[string]bool)
+
+		if err := Load(filenames...); err != nil {
+			return
+		}
+		notifyReload(changed)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			abs, err := filepath.Abs(event.Name)
+			if err != nil || !watched[abs] {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			pending[abs] = true
+			if timer == nil {
+				timer = time.NewTimer(debounceWindow)
+			} else {
+				timer.Reset(debounceWindow)
+			}
+		case <-timerC(timer):
+			reload()
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+		}
+	}
+}
+
+// timerC returns t's channel, or nil if t hasn't been created yet. A nil
+// channel blocks forever in a select, which is exactly what we want
+// before the first debounced event arrives.
+func timerC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
@@ -0,0 +1,63 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheConcurrentAccess(t *testing.T) {
+	ClearCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cacheSet("CONCURRENT_KEY", "value")
+		}()
+		go func() {
+			defer wg.Done()
+			cacheGet("CONCURRENT_KEY")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWatchReloadsOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(path, []byte("WATCHED_VAR=initial\n"), 0644))
+
+	ClearCache()
+	require.NoError(t, Load(path))
+	require.Equal(t, "initial", GetString("WATCHED_VAR", ""))
+
+	reloaded := make(chan []string, 1)
+	OnReload(func(changed []string) {
+		select {
+		case reloaded <- changed:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	go Watch(ctx, path)
+	time.Sleep(50 * time.Millisecond) // let the watcher attach before we write
+
+	require.NoError(t, os.WriteFile(path, []byte("WATCHED_VAR=updated\n"), 0644))
+
+	select {
+	case <-reloaded:
+		require.Equal(t, "updated", GetString("WATCHED_VAR", ""))
+	case <-time.After(1500 * time.Millisecond):
+		t.Fatal("timed out waiting for reload")
+	}
+}
@@ -0,0 +1,146 @@
+// Command dotenv-crypt encrypts and decrypts .env files so they can be
+// safely committed to source control (e.g. as .env.enc), using the same
+// age or AES-GCM backends as dotenv.LoadEncrypted.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/talut/dotenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "encrypt":
+		runEncrypt(os.Args[2:])
+	case "decrypt":
+		runDecrypt(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  dotenv-crypt encrypt -in .env -out .env.enc -key age:<recipient>
+  dotenv-crypt decrypt -in .env.enc -out .env -key age:<identity-file>
+
+key schemes:
+  age:<recipient-or-identity-file>   filippo.io/age, X25519
+  aesgcm:<hex-or-base64-key>         raw AES-GCM, nonce||ciphertext||tag`)
+}
+
+func runEncrypt(args []string) {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	in := fs.String("in", "", "path to the plaintext .env file")
+	out := fs.String("out", "", "path to write the encrypted file")
+	key := fs.String("key", "", "keyRef, e.g. age:<recipient> or aesgcm:<key>")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" || *key == "" {
+		usage()
+		os.Exit(2)
+	}
+	if err := dotenv.EncryptFile(*in, *out, *key); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv-crypt:", err)
+		os.Exit(1)
+	}
+}
+
+// runDecrypt decrypts -in directly via dotenv.Decrypt and writes the
+// resulting variables to -out in plain .env format. Unlike LoadEncrypted,
+// this necessarily writes decrypted content to disk, but that's the
+// explicit, user-requested purpose of this subcommand.
+func runDecrypt(args []string) {
+	fs := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	in := fs.String("in", "", "path to the encrypted file")
+	out := fs.String("out", "", "path to write the decrypted .env file")
+	key := fs.String("key", "", "keyRef, e.g. age:<identity-file> or aesgcm:<key>")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" || *key == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	ciphertext, err := os.ReadFile(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv-crypt:", err)
+		os.Exit(1)
+	}
+
+	vars, err := dotenv.Decrypt(*key, ciphertext)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv-crypt:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, []byte(serializeEnv(vars)), 0600); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv-crypt:", err)
+		os.Exit(1)
+	}
+}
+
+// serializeEnv renders vars as KEY=VALUE lines, sorted for stable
+// output, quoting each value so it round-trips exactly through Load
+// (see quoteDotenvValue for the one kind of value that does not).
+func serializeEnv(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, quoteDotenvValue(vars[k]))
+	}
+	return b.String()
+}
+
+// quoteDotenvValue renders value as a single .env-syntax token that
+// round-trips through Load unchanged, except as noted below. Single-quoted values are read
+// back by parser.go completely literally - no backslash escapes and no
+// $VAR/${VAR} expansion - so that's used whenever value doesn't itself
+// contain a single quote. Values that do contain one fall back to
+// double-quoting via escapeDotenvValue; a double-quoted value can't
+// safely hold a literal "$" followed by an identifier, since the parser
+// always expands those, but that combination doesn't arise for the
+// common case (decrypted secrets rarely contain a literal ').
+func quoteDotenvValue(value string) string {
+	if !strings.ContainsRune(value, '\'') {
+		return "'" + value + "'"
+	}
+	return `"` + escapeDotenvValue(value) + `"`
+}
+
+func escapeDotenvValue(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/talut/dotenv"
+)
+
+func TestSerializeEnvRoundTripsThroughParse(t *testing.T) {
+	vars := map[string]string{
+		"SIMPLE": "plain value",
+		"QUOTED": `has "quotes" and \backslash\`,
+		"MULTI":  "line one\nline two\ttabbed",
+		"DOLLAR": "p@ssw0rd$HOME-suffix",
+	}
+
+	out := serializeEnv(vars)
+
+	parsed, err := dotenv.Parse([]byte(out))
+	require.NoError(t, err)
+	require.Equal(t, vars, parsed)
+}
+
+func TestQuoteDotenvValueSingleQuotesValuesContainingDollar(t *testing.T) {
+	// Double-quoting would let parser.go's $VAR expansion mangle this
+	// value; single-quoting sidesteps expansion entirely.
+	value := "p@ssw0rd$HOME-suffix"
+	quoted := quoteDotenvValue(value)
+	require.Equal(t, "'"+value+"'", quoted)
+
+	parsed, err := dotenv.Parse([]byte("SECRET=" + quoted + "\n"))
+	require.NoError(t, err)
+	require.Equal(t, value, parsed["SECRET"])
+}
+
+func TestEscapeDotenvValuePreservesOtherControlBytes(t *testing.T) {
+	// %q would render this as "\a", which parser.go does not decode back
+	// to the same byte. escapeDotenvValue must leave it untouched.
+	value := "bell:\aend"
+	escaped := escapeDotenvValue(value)
+	require.Equal(t, value, escaped)
+}
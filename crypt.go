@@ -0,0 +1,258 @@
+package dotenv
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Decrypt decrypts ciphertext using the backend selected by keyRef (see
+// LoadEncrypted for the supported schemes) and parses the result as
+// .env syntax, returning the variables it defines. The decrypted
+// plaintext is held in memory only long enough to parse and is zeroed
+// before Decrypt returns; it is never written to disk.
+func Decrypt(keyRef string, ciphertext []byte) (map[string]string, error) {
+	plaintext, err := decrypt(keyRef, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	defer zero(plaintext)
+
+	return parseBytes(plaintext, false)
+}
+
+// LoadEncrypted decrypts one or more .env.enc files using the backend
+// selected by keyRef and loads the resulting variables the same way
+// Load does ("later file wins"). The decrypted plaintext is held in
+// memory only long enough to parse and is never written to disk.
+//
+// keyRef selects a backend by scheme:
+//
+//	age:<identity-or-identity-file>   decrypt with filippo.io/age (X25519)
+//	aesgcm:<hex-or-base64-key>        decrypt a nonce||ciphertext||tag blob
+//
+// If no filenames are given, ".env.enc" is used.
+func LoadEncrypted(keyRef string, filenames ...string) error {
+	if len(filenames) == 0 {
+		filenames = []string{".env.enc"}
+	}
+
+	merged := make(map[string]string)
+	for _, filename := range filenames {
+		_, err := os.Stat(filename)
+		if os.IsNotExist(err) {
+			continue
+		}
+		ciphertext, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		vars, err := Decrypt(keyRef, ciphertext)
+		if err != nil {
+			return fmt.Errorf("dotenv: decrypting %s: %w", filename, err)
+		}
+		for key, value := range vars {
+			merged[key] = value
+		}
+	}
+
+	return applyAndCache(merged)
+}
+
+// EncryptFile encrypts the file at src and writes the result to dst
+// using the backend selected by keyRef (see LoadEncrypted for the
+// supported schemes). dst is written with 0600 permissions.
+func EncryptFile(src, dst, keyRef string) error {
+	plaintext, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	defer zero(plaintext)
+
+	ciphertext, err := encrypt(keyRef, plaintext)
+	if err != nil {
+		return fmt.Errorf("dotenv: encrypting %s: %w", src, err)
+	}
+
+	return os.WriteFile(dst, ciphertext, 0600)
+}
+
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func splitKeyRef(keyRef string) (scheme, value string, err error) {
+	parts := strings.SplitN(keyRef, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("dotenv: invalid keyRef %q (expected \"scheme:value\")", keyRef)
+	}
+	return parts[0], parts[1], nil
+}
+
+func decrypt(keyRef string, ciphertext []byte) ([]byte, error) {
+	scheme, value, err := splitKeyRef(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scheme {
+	case "age":
+		return ageDecrypt(value, ciphertext)
+	case "aesgcm":
+		return aesgcmDecrypt(value, ciphertext)
+	default:
+		return nil, fmt.Errorf("dotenv: unknown keyRef scheme %q", scheme)
+	}
+}
+
+func encrypt(keyRef string, plaintext []byte) ([]byte, error) {
+	scheme, value, err := splitKeyRef(keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scheme {
+	case "age":
+		return ageEncrypt(value, plaintext)
+	case "aesgcm":
+		return aesgcmEncrypt(value, plaintext)
+	default:
+		return nil, fmt.Errorf("dotenv: unknown keyRef scheme %q", scheme)
+	}
+}
+
+// ageIdentities resolves an age: keyRef value to a set of identities,
+// accepting either a literal "AGE-SECRET-KEY-1..." identity or the path
+// to a file containing one or more identities.
+func ageIdentities(value string) ([]age.Identity, error) {
+	if strings.HasPrefix(value, "AGE-SECRET-KEY-") {
+		id, err := age.ParseX25519Identity(value)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Identity{id}, nil
+	}
+
+	f, err := os.Open(value)
+	if err != nil {
+		return nil, fmt.Errorf("reading age identity file: %w", err)
+	}
+	defer f.Close()
+	return age.ParseIdentities(f)
+}
+
+// ageRecipients resolves an age: keyRef value to a set of recipients,
+// accepting either a literal "age1..." recipient or the path to a file
+// containing one or more recipients (one per line).
+func ageRecipients(value string) ([]age.Recipient, error) {
+	if strings.HasPrefix(value, "age1") {
+		r, err := age.ParseX25519Recipient(value)
+		if err != nil {
+			return nil, err
+		}
+		return []age.Recipient{r}, nil
+	}
+
+	f, err := os.Open(value)
+	if err != nil {
+		return nil, fmt.Errorf("reading age recipients file: %w", err)
+	}
+	defer f.Close()
+	return age.ParseRecipients(f)
+}
+
+func ageDecrypt(value string, ciphertext []byte) ([]byte, error) {
+	identities, err := ageIdentities(value)
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.Decrypt(bytes.NewReader(ciphertext), identities...)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func ageEncrypt(value string, plaintext []byte) ([]byte, error) {
+	recipients, err := ageRecipients(value)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// aesKey decodes an aesgcm: keyRef value, trying hex first and then
+// standard base64, since both are common ways to hand a raw AES key to
+// a CLI without binary-unsafe shells mangling it.
+func aesKey(value string) ([]byte, error) {
+	if key, err := hex.DecodeString(value); err == nil {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("dotenv: aesgcm key is neither valid hex nor base64")
+}
+
+func aesgcmDecrypt(value string, ciphertext []byte) ([]byte, error) {
+	key, err := aesKey(value)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("dotenv: ciphertext shorter than nonce")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, nil)
+}
+
+func aesgcmEncrypt(value string, plaintext []byte) ([]byte, error) {
+	key, err := aesKey(value)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
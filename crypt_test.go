@@ -0,0 +1,77 @@
+package dotenv
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCMRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	keyRef := "aesgcm:" + hex.EncodeToString(key)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, ".env")
+	enc := filepath.Join(dir, ".env.enc")
+	require.NoError(t, os.WriteFile(src, []byte("FOO=bar\nBAZ=qux\n"), 0644))
+
+	require.NoError(t, EncryptFile(src, enc, keyRef))
+
+	ClearCache()
+	t.Setenv("FOO", "")
+	os.Unsetenv("FOO")
+	os.Unsetenv("BAZ")
+	require.NoError(t, LoadEncrypted(keyRef, enc))
+
+	require.Equal(t, "bar", os.Getenv("FOO"))
+	require.Equal(t, "qux", os.Getenv("BAZ"))
+}
+
+func TestAgeRoundTrip(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	recipientRef := "age:" + identity.Recipient().String()
+	identityRef := "age:" + identity.String()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, ".env")
+	enc := filepath.Join(dir, ".env.enc")
+	require.NoError(t, os.WriteFile(src, []byte("SECRET=shh\n"), 0644))
+
+	require.NoError(t, EncryptFile(src, enc, recipientRef))
+
+	ClearCache()
+	os.Unsetenv("SECRET")
+	require.NoError(t, LoadEncrypted(identityRef, enc))
+	require.Equal(t, "shh", os.Getenv("SECRET"))
+}
+
+func TestDecryptReturnsVarsWithoutTouchingProcessEnv(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	keyRef := "aesgcm:" + hex.EncodeToString(key)
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, ".env")
+	enc := filepath.Join(dir, ".env.enc")
+	require.NoError(t, os.WriteFile(src, []byte("ALREADY_SET=from_file\n"), 0644))
+	require.NoError(t, EncryptFile(src, enc, keyRef))
+
+	t.Setenv("ALREADY_SET", "from_env") // a different value already present in the process env
+	ciphertext, err := os.ReadFile(enc)
+	require.NoError(t, err)
+
+	vars, err := Decrypt(keyRef, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "from_file", vars["ALREADY_SET"])
+	require.Equal(t, "from_env", os.Getenv("ALREADY_SET"))
+}
@@ -4,30 +4,35 @@ import (
 	"log"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 )
 
-// cache is a map that stores the values of environment variables.
-// The key is the name of the environment variable and the value is the value of the environment variable.
-// This is used to avoid repeated lookups of environment variables, which can be expensive.
-var cache = make(map[string]string)
-
-// ClearCache clears the cache of environment variables.
-func ClearCache() {
-	cache = make(map[string]string)
-}
-
 // Load loads environment variables from one or more .env files.
 // Files are loaded in the order provided. If a key exists in multiple files,
 // the value from the last file will be used.
 // If no filenames are provided, it attempts to load from the default ".env" file.
 // Files that don't exist are skipped without error.
+// Malformed lines (a missing "=" or an unterminated quote) are skipped; see
+// LoadStrict to treat them as errors instead. An invalid variable name is
+// always an error and is returned as a *ParseError.
 func Load(filenames ...string) error {
+	return load(false, filenames...)
+}
+
+// LoadStrict behaves like Load, except any malformed line (a missing
+// "=" or an unterminated quote) aborts the load and returns a
+// *ParseError instead of being skipped. An invalid variable name aborts
+// the load in both Load and LoadStrict.
+func LoadStrict(filenames ...string) error {
+	return load(true, filenames...)
+}
+
+func load(strict bool, filenames ...string) error {
 	if len(filenames) == 0 {
 		filenames = []string{".env"}
 	}
 
+	merged := make(map[string]string)
 	for _, filename := range filenames {
 		_, err := os.Stat(filename)
 		if os.IsNotExist(err) {
@@ -38,51 +43,23 @@ func Load(filenames ...string) error {
 			return err
 		}
 
-		lines := strings.Split(string(contents), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
-			idx := strings.Index(line, "=")
-			if idx == -1 {
-				continue // Skip lines without equals sign
-			}
-			key := strings.TrimSpace(line[:idx])
-			value := ""
-			if idx+1 < len(line) {
-				value = strings.TrimSpace(line[idx+1:])
-			}
-			if len(value) > 1 {
-				if (value[0] == '"' && value[len(value)-1] == '"') ||
-					(value[0] == '\'' && value[len(value)-1] == '\'') {
-					value = value[1 : len(value)-1]
-				}
-			}
-
-			if err := os.Setenv(key, value); err != nil {
-				return err
-			}
+		vars, err := parseBytes(contents, strict)
+		if err != nil {
+			return err
+		}
+		for key, value := range vars {
+			merged[key] = value
 		}
 	}
-	ClearCache()
 
-	return nil
+	return applyAndCache(merged)
 }
 
 // GetString retrieves the value of the environment variable named by the key.
 // If the value is not set, the fallback value is returned.
 // The value is cached to avoid repeated lookups.
 func GetString(key, fallback string) string {
-	value, exists := cache[key]
-	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists || value == "" {
-			value = fallback
-		}
-		cache[key] = value
-	}
-	return value
+	return lockedLookupWithFallback(key, fallback)
 }
 
 // GetBool retrieves the value of the environment variable named by the key.
@@ -90,13 +67,9 @@ func GetString(key, fallback string) string {
 // The value is cached to avoid repeated lookups.
 // If the value is set but cannot be parsed as a boolean, a warning logged.
 func GetBool(key string, fallback bool) bool {
-	value, exists := cache[key]
+	value, exists := lockedLookup(key)
 	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists {
-			return fallback
-		}
-		cache[key] = value
+		return fallback
 	}
 	boolValue, err := strconv.ParseBool(value)
 	if err != nil {
@@ -111,13 +84,9 @@ func GetBool(key string, fallback bool) bool {
 // The value is cached to avoid repeated lookups.
 // If the value is set but cannot be parsed as an integer, a warning logged.
 func GetInt(key string, fallback int) int {
-	value, exists := cache[key]
+	value, exists := lockedLookup(key)
 	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists {
-			return fallback
-		}
-		cache[key] = value
+		return fallback
 	}
 	intValue, err := strconv.Atoi(value)
 	if err != nil {
@@ -132,13 +101,9 @@ func GetInt(key string, fallback int) int {
 // The value is cached to avoid repeated lookups.
 // If the value is set but cannot be parsed as a float, a warning logged.
 func GetFloat(key string, fallback float64) float64 {
-	value, exists := cache[key]
+	value, exists := lockedLookup(key)
 	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists {
-			return fallback
-		}
-		cache[key] = value
+		return fallback
 	}
 	floatValue, err := strconv.ParseFloat(value, 64)
 	if err != nil {
@@ -153,13 +118,9 @@ func GetFloat(key string, fallback float64) float64 {
 // The value is cached to avoid repeated lookups.
 // If the value is set but cannot be parsed as a duration, a warning logged.
 func GetDuration(key string, fallback time.Duration) time.Duration {
-	value, exists := cache[key]
+	value, exists := lockedLookup(key)
 	if !exists {
-		value, exists = os.LookupEnv(key)
-		if !exists {
-			return fallback
-		}
-		cache[key] = value
+		return fallback
 	}
 	durationValue, err := time.ParseDuration(value)
 	if err != nil {
@@ -0,0 +1,88 @@
+// Package dotenvtest provides test helpers for code that depends on
+// environment variables loaded through dotenv. It mirrors what
+// testing.T.Setenv gives you for a single os env var, but also restores
+// dotenv's internal cache so Get*/Unmarshal calls see consistent state
+// across tests.
+package dotenvtest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/talut/dotenv"
+)
+
+// Setenv sets key to value for the duration of the test, restoring the
+// previous value (or unsetting it, if it was not set) and clearing
+// dotenv's cache when the test completes.
+func Setenv(t testing.TB, key, value string) {
+	t.Helper()
+
+	previous, existed := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatalf("dotenvtest: failed to set %s: %v", key, err)
+	}
+	dotenv.ClearCache()
+
+	t.Cleanup(func() {
+		if existed {
+			os.Setenv(key, previous)
+		} else {
+			os.Unsetenv(key)
+		}
+		dotenv.ClearCache()
+	})
+}
+
+// WithEnv sets every key/value pair in kv for the duration of the test,
+// restoring each one independently on cleanup.
+func WithEnv(t testing.TB, kv map[string]string) {
+	t.Helper()
+	for key, value := range kv {
+		Setenv(t, key, value)
+	}
+}
+
+// LoadFixture parses content as .env syntax and sets each variable it
+// defines for the duration of the test, as Setenv would.
+func LoadFixture(t testing.TB, content string) {
+	t.Helper()
+
+	vars, err := dotenv.Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("dotenvtest: failed to parse fixture: %v", err)
+	}
+	for key, value := range vars {
+		Setenv(t, key, value)
+	}
+}
+
+// Snapshot captures the entire current environment and dotenv cache on
+// entry and restores both on cleanup. Like Setenv and WithEnv, it
+// mutates the real process environment (via os.Clearenv/os.Setenv on
+// cleanup), so it must not be used from a test running under
+// t.Parallel(): a concurrently-running sibling test can observe the
+// environment mid-wipe. Use it the same way you'd use testing.T.Setenv
+// — before any call to t.Parallel() in the test.
+func Snapshot(t testing.TB) {
+	t.Helper()
+
+	before := os.Environ()
+	restoreCache := dotenv.SetCacheForTesting(make(map[string]string))
+
+	t.Cleanup(func() {
+		restoreCache()
+		restoreEnviron(before)
+	})
+}
+
+func restoreEnviron(before []string) {
+	os.Clearenv()
+	for _, kv := range before {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			os.Setenv(parts[0], parts[1])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dotenvtest_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/talut/dotenv"
+	"github.com/talut/dotenv/dotenvtest"
+)
+
+func TestSetenvRestoresPreviousValue(t *testing.T) {
+	os.Setenv("DOTENVTEST_EXISTING", "original")
+	defer os.Unsetenv("DOTENVTEST_EXISTING")
+
+	t.Run("inner", func(t *testing.T) {
+		dotenvtest.Setenv(t, "DOTENVTEST_EXISTING", "overridden")
+		require.Equal(t, "overridden", os.Getenv("DOTENVTEST_EXISTING"))
+	})
+
+	require.Equal(t, "original", os.Getenv("DOTENVTEST_EXISTING"))
+}
+
+func TestSetenvUnsetsPreviouslyUnsetKey(t *testing.T) {
+	os.Unsetenv("DOTENVTEST_NEW")
+
+	t.Run("inner", func(t *testing.T) {
+		dotenvtest.Setenv(t, "DOTENVTEST_NEW", "value")
+		require.Equal(t, "value", os.Getenv("DOTENVTEST_NEW"))
+	})
+
+	_, exists := os.LookupEnv("DOTENVTEST_NEW")
+	require.False(t, exists)
+}
+
+func TestWithEnvSetsAllKeys(t *testing.T) {
+	t.Run("inner", func(t *testing.T) {
+		dotenvtest.WithEnv(t, map[string]string{
+			"DOTENVTEST_A": "1",
+			"DOTENVTEST_B": "2",
+		})
+		require.Equal(t, "1", os.Getenv("DOTENVTEST_A"))
+		require.Equal(t, "2", os.Getenv("DOTENVTEST_B"))
+	})
+
+	_, existsA := os.LookupEnv("DOTENVTEST_A")
+	_, existsB := os.LookupEnv("DOTENVTEST_B")
+	require.False(t, existsA)
+	require.False(t, existsB)
+}
+
+func TestLoadFixtureSetsParsedVars(t *testing.T) {
+	t.Run("inner", func(t *testing.T) {
+		dotenvtest.LoadFixture(t, "FIXTURE_FOO=bar\nFIXTURE_BAZ=qux\n")
+		require.Equal(t, "bar", os.Getenv("FIXTURE_FOO"))
+		require.Equal(t, "qux", os.Getenv("FIXTURE_BAZ"))
+	})
+}
+
+func TestSnapshotRestoresEnvironment(t *testing.T) {
+	os.Setenv("DOTENVTEST_SNAPSHOT", "before")
+	defer os.Unsetenv("DOTENVTEST_SNAPSHOT")
+
+	t.Run("inner", func(t *testing.T) {
+		dotenvtest.Snapshot(t)
+		os.Setenv("DOTENVTEST_SNAPSHOT", "during")
+		os.Setenv("DOTENVTEST_SNAPSHOT_NEW", "added")
+		require.Equal(t, "during", dotenv.GetString("DOTENVTEST_SNAPSHOT", ""))
+	})
+
+	require.Equal(t, "before", os.Getenv("DOTENVTEST_SNAPSHOT"))
+	_, exists := os.LookupEnv("DOTENVTEST_SNAPSHOT_NEW")
+	require.False(t, exists)
+}
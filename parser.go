@@ -0,0 +1,322 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// keyPattern matches valid environment variable identifiers: a leading
+// letter or underscore followed by letters, digits, or underscores.
+var keyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expandPattern matches $VAR and ${VAR} references inside a value.
+var expandPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ParseError describes a malformed line encountered while parsing a
+// .env file. Line numbers are 1-indexed.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("dotenv: line %d: %s", e.Line, e.Msg)
+}
+
+// parser is a small hand-written state machine that tokenizes .env file
+// contents. It understands quoting, escaping, multi-line values, inline
+// comments, and variable expansion.
+type parser struct {
+	src    []byte
+	pos    int
+	line   int
+	strict bool
+	vars   map[string]string
+}
+
+// Parse reads the full contents of r-style .env bytes and returns the
+// key/value pairs it defines. Values are expanded against keys already
+// defined earlier in the same file and against the current process
+// environment; variables that are still undefined expand to "".
+//
+// Malformed lines (missing "=", unterminated quotes) are skipped. Keys
+// that are not valid identifiers always produce an error, since silently
+// dropping them would hide a typo rather than a stylistic choice.
+func Parse(content []byte) (map[string]string, error) {
+	return parseBytes(content, false)
+}
+
+// ParseStrict behaves like Parse but treats any malformed line as an
+// error instead of skipping it.
+func ParseStrict(content []byte) (map[string]string, error) {
+	return parseBytes(content, true)
+}
+
+func parseBytes(content []byte, strict bool) (map[string]string, error) {
+	content = stripBOM(content)
+	p := &parser{src: content, line: 1, strict: strict, vars: make(map[string]string)}
+	if err := p.run(); err != nil {
+		return nil, err
+	}
+	return p.vars, nil
+}
+
+func stripBOM(b []byte) []byte {
+	const bom = "\xEF\xBB\xBF"
+	if strings.HasPrefix(string(b), bom) {
+		return b[len(bom):]
+	}
+	return b
+}
+
+func (p *parser) run() error {
+	for {
+		p.skipBlankAndComments()
+		if p.eof() {
+			return nil
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) advance() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipBlankAndComments consumes blank lines and whole-line comments
+// ("# ..."), leaving pos at the start of the next meaningful line.
+func (p *parser) skipBlankAndComments() {
+	for !p.eof() {
+		start := p.pos
+		for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r' || p.peek() == '\n') {
+			p.advance()
+		}
+		if !p.eof() && p.peek() == '#' {
+			p.skipToEOL()
+			continue
+		}
+		if p.pos == start {
+			return
+		}
+	}
+}
+
+func (p *parser) skipToEOL() {
+	for !p.eof() && p.peek() != '\n' {
+		p.advance()
+	}
+}
+
+// statement parses a single "[export] KEY=VALUE [# comment]" entry.
+func (p *parser) statement() error {
+	lineStart := p.line
+
+	if p.hasPrefix("export") {
+		after := p.pos + len("export")
+		if after < len(p.src) && (p.src[after] == ' ' || p.src[after] == '\t') {
+			p.pos = after
+			for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+				p.advance()
+			}
+		}
+	}
+
+	key := p.readKey()
+	if key == "" {
+		return p.malformed(lineStart, "expected a variable name")
+	}
+	p.skipInlineSpace()
+	if p.peek() != '=' {
+		return p.malformed(lineStart, fmt.Sprintf("missing '=' after %q", key))
+	}
+	if !keyPattern.MatchString(key) {
+		return &ParseError{Line: lineStart, Msg: fmt.Sprintf("invalid variable name %q", key)}
+	}
+	p.advance() // consume '='
+	p.skipInlineSpace()
+
+	valueStart, valueLine := p.pos, p.line
+	value, quoted, singleQuoted, err := p.readValue(lineStart)
+	if err != nil {
+		// Rewind past a runaway quoted value so only this line is skipped.
+		p.pos, p.line = valueStart, valueLine
+		return p.malformed(lineStart, err.Error())
+	}
+	if !quoted {
+		value = strings.TrimRight(value, " \t\r")
+	}
+	if !singleQuoted {
+		value = p.expand(value)
+	}
+	p.vars[key] = value
+
+	p.skipToEOL()
+	return nil
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(string(p.src[p.pos:]), s)
+}
+
+func (p *parser) skipInlineSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.advance()
+	}
+}
+
+// readKey consumes identifier-like characters up to '=' or whitespace.
+func (p *parser) readKey() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '=' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			break
+		}
+		p.advance()
+	}
+	return string(p.src[start:p.pos])
+}
+
+// readValue parses a quoted or unquoted value starting at the current
+// position. It returns the raw value, whether it was quoted at all, and
+// whether it was single-quoted (and therefore literal, no expansion).
+func (p *parser) readValue(lineStart int) (value string, quoted bool, singleQuoted bool, err error) {
+	if p.eof() || p.peek() == '\n' {
+		return "", false, false, nil
+	}
+
+	switch p.peek() {
+	case '"':
+		v, err := p.readQuoted('"', true)
+		if err != nil {
+			return "", false, false, err
+		}
+		return v, true, false, nil
+	case '\'':
+		v, err := p.readQuoted('\'', false)
+		if err != nil {
+			return "", false, false, err
+		}
+		return v, true, true, nil
+	default:
+		return p.readUnquoted(), false, false, nil
+	}
+}
+
+// readQuoted consumes up to the matching closing quote, which may be on
+// a later physical line. When unescape is true, backslash escapes for
+// \n, \t, \r, \" and \\ are resolved; otherwise the contents are taken
+// literally, as single-quoted values are.
+func (p *parser) readQuoted(quote byte, unescape bool) (string, error) {
+	p.advance() // opening quote
+	var b strings.Builder
+	for {
+		if p.eof() {
+			return "", fmt.Errorf("unterminated %c-quoted value", quote)
+		}
+		c := p.peek()
+		if c == quote {
+			p.advance()
+			return b.String(), nil
+		}
+		if unescape && c == '\\' && p.pos+1 < len(p.src) {
+			next := p.src[p.pos+1]
+			switch next {
+			case 'n':
+				b.WriteByte('\n')
+				p.advance()
+				p.advance()
+				continue
+			case 't':
+				b.WriteByte('\t')
+				p.advance()
+				p.advance()
+				continue
+			case 'r':
+				b.WriteByte('\r')
+				p.advance()
+				p.advance()
+				continue
+			case '"':
+				b.WriteByte('"')
+				p.advance()
+				p.advance()
+				continue
+			case '\\':
+				b.WriteByte('\\')
+				p.advance()
+				p.advance()
+				continue
+			}
+		}
+		b.WriteByte(c)
+		p.advance()
+	}
+}
+
+// readUnquoted consumes an unquoted value up to end of line, stopping at
+// an inline comment (a '#' preceded by whitespace) while leaving a bare
+// '#' inside the value (e.g. "foo#baz") untouched.
+func (p *parser) readUnquoted() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		if p.peek() == '#' && p.pos > 0 {
+			prev := p.src[p.pos-1]
+			if prev == ' ' || prev == '\t' {
+				break
+			}
+		}
+		p.advance()
+	}
+	return string(p.src[start:p.pos])
+}
+
+// malformed records a parse failure. In strict mode this always stops
+// parsing; in lenient mode it skips the offending line and continues.
+func (p *parser) malformed(line int, msg string) error {
+	if p.strict {
+		return &ParseError{Line: line, Msg: msg}
+	}
+	p.skipToEOL()
+	return nil
+}
+
+// expand replaces $VAR and ${VAR} references with values already parsed
+// from this file, falling back to the current environment and then to
+// the empty string.
+func (p *parser) expand(value string) string {
+	return expandPattern.ReplaceAllStringFunc(value, func(match string) string {
+		sub := expandPattern.FindStringSubmatch(match)
+		name := sub[1]
+		if name == "" {
+			name = sub[2]
+		}
+		if v, ok := p.vars[name]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		return ""
+	})
+}
@@ -0,0 +1,84 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBasics(t *testing.T) {
+	vars, err := Parse([]byte("FOO=bar\nexport BAR=baz\n"))
+	require.NoError(t, err)
+	require.Equal(t, "bar", vars["FOO"])
+	require.Equal(t, "baz", vars["BAR"])
+}
+
+func TestParseQuotingAndEscapes(t *testing.T) {
+	content := []byte("DOUBLE=\"line1\\nline2\\ttabbed\"\nSINGLE='raw\\nvalue'\n")
+	vars, err := Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "line1\nline2\ttabbed", vars["DOUBLE"])
+	require.Equal(t, "raw\\nvalue", vars["SINGLE"])
+}
+
+func TestParseMultilineQuotedValue(t *testing.T) {
+	content := []byte("CERT=\"-----BEGIN-----\nabc123\n-----END-----\"\nAFTER=ok\n")
+	vars, err := Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "-----BEGIN-----\nabc123\n-----END-----", vars["CERT"])
+	require.Equal(t, "ok", vars["AFTER"])
+}
+
+func TestParseInlineComments(t *testing.T) {
+	content := []byte("FOO=bar # a comment\nBAZ=foo#baz\nQUOTED=\"bar # not a comment\"\n")
+	vars, err := Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "bar", vars["FOO"])
+	require.Equal(t, "foo#baz", vars["BAZ"])
+	require.Equal(t, "bar # not a comment", vars["QUOTED"])
+}
+
+func TestParseVariableExpansion(t *testing.T) {
+	t.Setenv("PARSE_EXPAND_FROM_ENV", "env_value")
+	content := []byte("HOST=localhost\nURL=http://${HOST}:8080\nFROM_ENV=$PARSE_EXPAND_FROM_ENV\nUNDEFINED=$NOPE_NOT_SET")
+	vars, err := Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "http://localhost:8080", vars["URL"])
+	require.Equal(t, "env_value", vars["FROM_ENV"])
+	require.Equal(t, "", vars["UNDEFINED"])
+}
+
+func TestParseBOMStripped(t *testing.T) {
+	content := append([]byte("\xEF\xBB\xBF"), []byte("FOO=bar\n")...)
+	vars, err := Parse(content)
+	require.NoError(t, err)
+	require.Equal(t, "bar", vars["FOO"])
+}
+
+func TestParseInvalidIdentifier(t *testing.T) {
+	_, err := Parse([]byte("1FOO=bar\n"))
+	require.Error(t, err)
+
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, 1, parseErr.Line)
+}
+
+func TestParseStrictRejectsMalformedLines(t *testing.T) {
+	_, err := Parse([]byte("not_an_assignment\nFOO=bar\n"))
+	require.NoError(t, err)
+
+	_, err = ParseStrict([]byte("not_an_assignment\nFOO=bar\n"))
+	require.Error(t, err)
+}
+
+func TestLoadStrictPropagatesParseError(t *testing.T) {
+	path := ".env.invalid"
+	require.NoError(t, os.WriteFile(path, []byte("9BAD=nope\n"), 0644))
+	defer os.Remove(path)
+
+	ClearCache()
+	err := LoadStrict(path)
+	require.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package dotenv
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetOrFile retrieves the value of the environment variable named by
+// key. If key is unset (or empty) but key+"_FILE" is set, it reads the
+// file at that path and returns its contents with trailing whitespace
+// trimmed. This is the common pattern for consuming Docker/Kubernetes
+// secrets that are mounted as files rather than passed as plain
+// environment variables. The resolved value participates in the same
+// cache as the Get* helpers.
+func GetOrFile(key string) string {
+	if v, ok := lookupCached(key); ok && v != "" {
+		return v
+	}
+
+	fileKey := key + "_FILE"
+	path, ok := lookupCached(fileKey)
+	if !ok || path == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read %s (%s): %v", fileKey, path, err)
+		return ""
+	}
+
+	value := strings.TrimRight(string(data), " \t\r\n")
+	cacheSet(key, value)
+	return value
+}
+
+// GetBoolOrFile behaves like GetOrFile, parsing the resolved value as a
+// boolean. If the value is unset or cannot be parsed, the fallback is
+// returned; a value that cannot be parsed also logs a warning.
+func GetBoolOrFile(key string, fallback bool) bool {
+	raw := GetOrFile(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("Failed to parse %s as bool: %v", key, err)
+		return fallback
+	}
+	return value
+}
+
+// GetIntOrFile behaves like GetOrFile, parsing the resolved value as an
+// integer. If the value is unset or cannot be parsed, the fallback is
+// returned; a value that cannot be parsed also logs a warning.
+func GetIntOrFile(key string, fallback int) int {
+	raw := GetOrFile(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Failed to parse %s as int: %v", key, err)
+		return fallback
+	}
+	return value
+}
+
+// GetFloatOrFile behaves like GetOrFile, parsing the resolved value as a
+// float. If the value is unset or cannot be parsed, the fallback is
+// returned; a value that cannot be parsed also logs a warning.
+func GetFloatOrFile(key string, fallback float64) float64 {
+	raw := GetOrFile(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("Failed to parse %s as float: %v", key, err)
+		return fallback
+	}
+	return value
+}
+
+// GetDurationOrFile behaves like GetOrFile, parsing the resolved value
+// as a time.Duration. If the value is unset or cannot be parsed, the
+// fallback is returned; a value that cannot be parsed also logs a warning.
+func GetDurationOrFile(key string, fallback time.Duration) time.Duration {
+	raw := GetOrFile(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("Failed to parse %s as duration: %v", key, err)
+		return fallback
+	}
+	return value
+}
+
+// GetWithFallback resolves each group of aliases to the first one that
+// is set to a non-empty value, returning a map keyed by each group's
+// first (canonical) alias. Groups are intended for renamed or
+// vendor-specific variables, e.g.:
+//
+//	GetWithFallback([]string{"APP_DB_URL", "DATABASE_URL"})
+//
+// If a group has no alias set, no entry is added for it and its group is
+// named in the returned error, which aggregates every such group rather
+// than failing on the first one.
+func GetWithFallback(groups ...[]string) (map[string]string, error) {
+	result := make(map[string]string, len(groups))
+	verr := &ValidationError{}
+
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		resolved := false
+		for _, alias := range group {
+			if v, ok := lookupCached(alias); ok && v != "" {
+				result[group[0]] = v
+				resolved = true
+				break
+			}
+		}
+		if !resolved {
+			verr.add("none of %s is set", strings.Join(group, ", "))
+		}
+	}
+
+	if len(verr.Errors) > 0 {
+		return result, verr
+	}
+	return result, nil
+}
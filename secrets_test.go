@@ -0,0 +1,68 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrFileReadsFileWhenKeyUnset(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "db_password")
+	require.NoError(t, os.WriteFile(secretPath, []byte("s3cret\n"), 0600))
+
+	t.Setenv("DB_PASSWORD_FILE", secretPath)
+	ClearCache()
+
+	require.Equal(t, "s3cret", GetOrFile("DB_PASSWORD"))
+}
+
+func TestGetOrFilePrefersDirectValue(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "inline")
+	ClearCache()
+
+	require.Equal(t, "inline", GetOrFile("DB_PASSWORD"))
+}
+
+func TestGetIntOrFileFallback(t *testing.T) {
+	ClearCache()
+	require.Equal(t, 42, GetIntOrFile("MISSING_PORT", 42))
+}
+
+func TestGetWithFallback(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://fallback")
+	ClearCache()
+
+	result, err := GetWithFallback([]string{"APP_DB_URL", "DATABASE_URL"})
+	require.NoError(t, err)
+	require.Equal(t, "postgres://fallback", result["APP_DB_URL"])
+}
+
+func TestGetWithFallbackReportsUnsetGroups(t *testing.T) {
+	ClearCache()
+	_, err := GetWithFallback([]string{"TOTALLY_UNSET_A", "TOTALLY_UNSET_B"})
+	require.Error(t, err)
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Len(t, verr.Errors, 1)
+}
+
+type secretConfig struct {
+	Password string `env:"SECRET_CFG_PASSWORD" envFile:"true"`
+}
+
+func TestUnmarshalWithEnvFileTag(t *testing.T) {
+	dir := t.TempDir()
+	secretPath := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(secretPath, []byte("hunter2"), 0600))
+
+	t.Setenv("SECRET_CFG_PASSWORD_FILE", secretPath)
+	ClearCache()
+
+	var cfg secretConfig
+	require.NoError(t, Unmarshal(&cfg))
+	require.Equal(t, "hunter2", cfg.Password)
+}
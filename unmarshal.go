@@ -0,0 +1,275 @@
+package dotenv
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError aggregates every missing or invalid field encountered
+// while unmarshalling, so callers can report all problems at once
+// instead of failing on the first one.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("dotenv: %d validation error(s): %s", len(e.Errors), strings.Join(e.Errors, "; "))
+}
+
+func (e *ValidationError) add(format string, args ...interface{}) {
+	e.Errors = append(e.Errors, fmt.Sprintf(format, args...))
+}
+
+// Unmarshal populates the fields of v, which must be a non-nil pointer
+// to a struct, from environment variables using the `env` struct tag.
+//
+// Tag syntax: `env:"KEY,required,default=foo"`. Nested structs are
+// recursed into, optionally prefixing their fields' keys with
+// `envPrefix:"DB_"`. Slice fields are split on `envSeparator:","` (comma
+// by default) and map fields additionally split each entry on
+// `envKeyValSeparator:":"` (colon by default). A field tagged
+// `envFile:"true"` is resolved via GetOrFile instead of a plain lookup,
+// so it transparently supports the KEY/KEY_FILE secret-file convention.
+//
+// If any required key is missing, or any value fails to parse for its
+// field type, Unmarshal returns a *ValidationError listing every such
+// field rather than stopping at the first one.
+func Unmarshal(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dotenv: Unmarshal requires a non-nil pointer to a struct, got %T", v)
+	}
+
+	verr := &ValidationError{}
+	unmarshalStruct(rv.Elem(), "", verr)
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// MustUnmarshal behaves like Unmarshal but panics if it returns an error.
+func MustUnmarshal(v interface{}) {
+	if err := Unmarshal(v); err != nil {
+		panic(err)
+	}
+}
+
+func unmarshalStruct(sv reflect.Value, prefix string, verr *ValidationError) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		fv := sv.Field(i)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			nestedPrefix := prefix + field.Tag.Get("envPrefix")
+			unmarshalStruct(fv, nestedPrefix, verr)
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+		opts := parseEnvTag(tag)
+		if sep := field.Tag.Get("envSeparator"); sep != "" {
+			opts.separator = sep
+		}
+		if kvSep := field.Tag.Get("envKeyValSeparator"); kvSep != "" {
+			opts.kvSeparator = kvSep
+		}
+		opts.envFile = field.Tag.Get("envFile") == "true"
+		key := prefix + opts.key
+		if key == "" {
+			verr.add("field %s: env tag has no key", field.Name)
+			continue
+		}
+
+		var raw string
+		var exists bool
+		if opts.envFile {
+			raw = GetOrFile(key)
+			exists = raw != ""
+		} else {
+			raw, exists = lookupCached(key)
+		}
+		if !exists || raw == "" {
+			if opts.required {
+				verr.add("%s: required environment variable is not set", key)
+				continue
+			}
+			if opts.hasDefault {
+				raw = opts.defaultValue
+			} else {
+				continue
+			}
+		}
+
+		setFieldFromValue(fv, field, key, raw, opts, verr)
+	}
+}
+
+func setFieldFromValue(fv reflect.Value, field reflect.StructField, key, raw string, opts envTagOptions, verr *ValidationError) {
+	switch {
+	case field.Type == reflect.TypeOf(time.Duration(0)):
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			verr.add("%s: invalid duration %q: %v", key, raw, err)
+			return
+		}
+		fv.Set(reflect.ValueOf(d))
+	case field.Type == reflect.TypeOf(time.Time{}):
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			verr.add("%s: invalid RFC3339 time %q: %v", key, raw, err)
+			return
+		}
+		fv.Set(reflect.ValueOf(ts))
+	case field.Type == reflect.TypeOf(&url.URL{}):
+		u, err := url.Parse(raw)
+		if err != nil {
+			verr.add("%s: invalid URL %q: %v", key, raw, err)
+			return
+		}
+		fv.Set(reflect.ValueOf(u))
+	case field.Type.Kind() == reflect.Slice:
+		setSliceField(fv, field, key, raw, opts, verr)
+	case field.Type.Kind() == reflect.Map:
+		setMapField(fv, field, key, raw, opts, verr)
+	default:
+		setScalarField(fv, field, key, raw, verr)
+	}
+}
+
+func setScalarField(fv reflect.Value, field reflect.StructField, key, raw string, verr *ValidationError) {
+	switch field.Type.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			verr.add("%s: invalid bool %q: %v", key, raw, err)
+			return
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type.Bits())
+		if err != nil {
+			verr.add("%s: invalid integer %q: %v", key, raw, err)
+			return
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, field.Type.Bits())
+		if err != nil {
+			verr.add("%s: invalid unsigned integer %q: %v", key, raw, err)
+			return
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, field.Type.Bits())
+		if err != nil {
+			verr.add("%s: invalid float %q: %v", key, raw, err)
+			return
+		}
+		fv.SetFloat(f)
+	default:
+		verr.add("%s: unsupported field type %s", key, field.Type)
+	}
+}
+
+func setSliceField(fv reflect.Value, field reflect.StructField, key, raw string, opts envTagOptions, verr *ValidationError) {
+	if raw == "" {
+		return
+	}
+	parts := strings.Split(raw, opts.separator)
+	slice := reflect.MakeSlice(field.Type, len(parts), len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		elem := slice.Index(i)
+		switch field.Type.Elem().Kind() {
+		case reflect.String:
+			elem.SetString(part)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				verr.add("%s: invalid integer element %q: %v", key, part, err)
+				return
+			}
+			elem.SetInt(n)
+		default:
+			verr.add("%s: unsupported slice element type %s", key, field.Type.Elem())
+			return
+		}
+	}
+	fv.Set(slice)
+}
+
+func setMapField(fv reflect.Value, field reflect.StructField, key, raw string, opts envTagOptions, verr *ValidationError) {
+	if raw == "" {
+		return
+	}
+	if field.Type.Key().Kind() != reflect.String || field.Type.Elem().Kind() != reflect.String {
+		verr.add("%s: unsupported map type %s (only map[string]string is supported)", key, field.Type)
+		return
+	}
+	m := reflect.MakeMap(field.Type)
+	for _, entry := range strings.Split(raw, opts.separator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, opts.kvSeparator, 2)
+		if len(kv) != 2 {
+			verr.add("%s: invalid map entry %q (expected key%svalue)", key, entry, opts.kvSeparator)
+			continue
+		}
+		m.SetMapIndex(reflect.ValueOf(kv[0]), reflect.ValueOf(kv[1]))
+	}
+	fv.Set(m)
+}
+
+// envTagOptions is the parsed form of an `env:"..."` tag plus its
+// sibling `envSeparator`/`envKeyValSeparator` tags.
+type envTagOptions struct {
+	key          string
+	required     bool
+	hasDefault   bool
+	defaultValue string
+	envFile      bool
+	separator    string
+	kvSeparator  string
+}
+
+func parseEnvTag(tag string) envTagOptions {
+	opts := envTagOptions{separator: ",", kvSeparator: ":"}
+	parts := strings.Split(tag, ",")
+	if len(parts) > 0 {
+		opts.key = strings.TrimSpace(parts[0])
+	}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "required":
+			opts.required = true
+		case strings.HasPrefix(part, "default="):
+			opts.hasDefault = true
+			opts.defaultValue = strings.TrimPrefix(part, "default=")
+		}
+	}
+	return opts
+}
+
+// lookupCached returns a key's value the same way the Get* helpers do:
+// from cache if present, otherwise from the process environment, atomic
+// with respect to a concurrent reload (see lockedLookup).
+func lookupCached(key string) (string, bool) {
+	return lockedLookup(key)
+}
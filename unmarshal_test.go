@@ -0,0 +1,70 @@
+package dotenv
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type dbConfig struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT,default=5432"`
+}
+
+type appConfig struct {
+	Name     string        `env:"APP_NAME,required"`
+	Debug    bool          `env:"APP_DEBUG,default=false"`
+	Timeout  time.Duration `env:"APP_TIMEOUT,default=30s"`
+	Tags     []string      `env:"APP_TAGS" envSeparator:"|"`
+	Labels   map[string]string `env:"APP_LABELS" envKeyValSeparator:"="`
+	Endpoint *url.URL      `env:"APP_ENDPOINT"`
+	DB       dbConfig      `envPrefix:"DB_"`
+}
+
+func TestUnmarshalPopulatesFields(t *testing.T) {
+	t.Setenv("APP_NAME", "myapp")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_TAGS", "a|b|c")
+	t.Setenv("APP_LABELS", "env=prod,team=core")
+	t.Setenv("APP_ENDPOINT", "https://example.com/api")
+	t.Setenv("DB_HOST", "db.internal")
+	ClearCache()
+
+	var cfg appConfig
+	err := Unmarshal(&cfg)
+	require.NoError(t, err)
+
+	require.Equal(t, "myapp", cfg.Name)
+	require.True(t, cfg.Debug)
+	require.Equal(t, 30*time.Second, cfg.Timeout)
+	require.Equal(t, []string{"a", "b", "c"}, cfg.Tags)
+	require.Equal(t, map[string]string{"env": "prod", "team": "core"}, cfg.Labels)
+	require.Equal(t, "https://example.com/api", cfg.Endpoint.String())
+	require.Equal(t, "db.internal", cfg.DB.Host)
+	require.Equal(t, 5432, cfg.DB.Port)
+}
+
+func TestUnmarshalAggregatesValidationErrors(t *testing.T) {
+	ClearCache()
+
+	var cfg appConfig
+	err := Unmarshal(&cfg)
+	require.Error(t, err)
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.GreaterOrEqual(t, len(verr.Errors), 2) // APP_NAME and DB_HOST both missing
+}
+
+func TestUnmarshalRejectsNonPointer(t *testing.T) {
+	err := Unmarshal(appConfig{})
+	require.Error(t, err)
+}
+
+func TestMustUnmarshalPanicsOnError(t *testing.T) {
+	ClearCache()
+	var cfg appConfig
+	require.Panics(t, func() { MustUnmarshal(&cfg) })
+}